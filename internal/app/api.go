@@ -9,12 +9,15 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	protoV1 "github.com/golang/protobuf/proto"
 	"github.com/mitchellh/mapstructure"
 	"github.com/wailsapp/wails"
 	"github.com/wailsapp/wails/cmd"
 	"github.com/wailsapp/wails/lib/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -25,15 +28,17 @@ import (
 	"google.golang.org/protobuf/types/dynamicpb"
 )
 
-const defaultWorkspaceKey = "wksp_default"
-
 type api struct {
 	runtime          *wails.Runtime
 	logger           *logger.CustomLogger
 	client           *client
 	store            *store
+	workspaceID      string
+	opts             options
 	protofiles       *protoregistry.Files
 	streamReq        chan proto.Message
+	stream           grpc.ClientStream
+	currentHistory   *historyEntry
 	cancelMonitoring context.CancelFunc
 	cancelInFlight   context.CancelFunc
 	mu               sync.Mutex
@@ -90,12 +95,29 @@ func (a *api) WailsShutdown() {
 	}
 }
 
-// GetWorkspaceOptions gets the workspace options from the store
+// GetWorkspaceOptions gets the options for the active workspace from the
+// store, resolving (and if necessary creating) the active workspace on
+// first call.
 func (a *api) GetWorkspaceOptions() (*options, error) {
-	val, err := a.store.get([]byte(defaultWorkspaceKey))
+	if a.workspaceID == "" {
+		id, err := a.currentOrDefaultWorkspace()
+		if err != nil {
+			return nil, err
+		}
+		a.workspaceID = id
+	}
+
+	return a.workspaceOptions(a.workspaceID)
+}
+
+func (a *api) workspaceOptions(id string) (*options, error) {
+	val, err := a.store.get(workspaceDataKey(id))
 	if err != nil {
 		return nil, err
 	}
+	if val == nil {
+		return &options{}, nil
+	}
 
 	var opts *options
 	dec := gob.NewDecoder(bytes.NewBuffer(val))
@@ -104,6 +126,186 @@ func (a *api) GetWorkspaceOptions() (*options, error) {
 	return opts, err
 }
 
+// currentOrDefaultWorkspace returns the id of the last active workspace,
+// creating a "Default" workspace on first run.
+func (a *api) currentOrDefaultWorkspace() (string, error) {
+	id, err := a.store.get([]byte(currentWorkspaceKey))
+	if err != nil {
+		return "", err
+	}
+	if id != nil {
+		return string(id), nil
+	}
+
+	workspaces, err := a.store.listWorkspaces()
+	if err != nil {
+		return "", err
+	}
+	if len(workspaces) > 0 {
+		return workspaces[0].ID, nil
+	}
+
+	ws, err := a.CreateWorkspace("Default")
+	if err != nil {
+		return "", err
+	}
+	return ws.ID, nil
+}
+
+// ListWorkspaces returns every workspace in this store.
+func (a *api) ListWorkspaces() ([]workspace, error) {
+	return a.store.listWorkspaces()
+}
+
+// CreateWorkspace creates a new, empty workspace and returns it.
+func (a *api) CreateWorkspace(name string) (*workspace, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	ws := workspace{ID: id, Name: name}
+
+	workspaces, err := a.store.listWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	workspaces = append(workspaces, ws)
+	if err := a.store.saveWorkspaces(workspaces); err != nil {
+		return nil, err
+	}
+
+	return &ws, nil
+}
+
+// RenameWorkspace renames the workspace identified by id.
+func (a *api) RenameWorkspace(id, name string) error {
+	workspaces, err := a.store.listWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	for i := range workspaces {
+		if workspaces[i].ID == id {
+			workspaces[i].Name = name
+			return a.store.saveWorkspaces(workspaces)
+		}
+	}
+
+	return fmt.Errorf("app: workspace %q not found", id)
+}
+
+// DeleteWorkspace removes the workspace identified by id along with its
+// persisted options and request history.
+func (a *api) DeleteWorkspace(id string) error {
+	workspaces, err := a.store.listWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	kept := workspaces[:0]
+	found := false
+	for _, ws := range workspaces {
+		if ws.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, ws)
+	}
+	if !found {
+		return fmt.Errorf("app: workspace %q not found", id)
+	}
+
+	if err := a.store.saveWorkspaces(kept); err != nil {
+		return err
+	}
+	if err := a.store.delete(workspaceDataKey(id)); err != nil {
+		return err
+	}
+	if err := a.store.delete(workspaceHistoryKey(id)); err != nil {
+		return err
+	}
+
+	if a.workspaceID != id {
+		return nil
+	}
+
+	// The active workspace was just deleted: switch to another
+	// workspace, or create a fresh default one if that was the last
+	// one, so a.workspaceID and the persisted currentWorkspaceKey never
+	// keep pointing at the now-deleted id.
+	if len(kept) > 0 {
+		return a.SwitchWorkspace(kept[0].ID)
+	}
+
+	ws, err := a.CreateWorkspace("Default")
+	if err != nil {
+		return err
+	}
+	return a.SwitchWorkspace(ws.ID)
+}
+
+// SwitchWorkspace makes id the active workspace and reconnects using its
+// persisted options.
+func (a *api) SwitchWorkspace(id string) error {
+	if err := a.store.set([]byte(currentWorkspaceKey), []byte(id)); err != nil {
+		return err
+	}
+	a.workspaceID = id
+
+	opts, err := a.workspaceOptions(id)
+	if err != nil {
+		return err
+	}
+	return a.Connect(opts)
+}
+
+// ListHistory returns past Send invocations recorded for workspaceID,
+// optionally filtered down to a single method's full name.
+func (a *api) ListHistory(workspaceID, methodFilter string) ([]historyEntry, error) {
+	history, err := a.store.listHistory(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if methodFilter == "" {
+		return history, nil
+	}
+
+	var filtered []historyEntry
+	for _, h := range history {
+		if h.Method == methodFilter {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered, nil
+}
+
+// ReplayHistory switches the request editor to the given history entry's
+// method and re-sends the entry's recorded request and metadata against
+// the active workspace. The editor receives the method's input fields
+// only; the recorded request values are not sent to it.
+func (a *api) ReplayHistory(id string) error {
+	history, err := a.store.listHistory(a.workspaceID)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range history {
+		if h.ID != id {
+			continue
+		}
+
+		md, err := a.getMethodDesc(h.Method)
+		if err != nil {
+			return err
+		}
+		a.runtime.Events.Emit(eventMethodInputChanged, messageViewFromDesc(md.Input()))
+
+		return a.SendWithMetadata(h.Method, []byte(h.RequestJSON), h.Metadata)
+	}
+
+	return fmt.Errorf("app: history entry %q not found", id)
+}
+
 // Connect will attempt to connect a grpc server and parse any proto files
 func (a *api) Connect(data interface{}) error {
 	var opts options
@@ -125,6 +327,7 @@ func (a *api) Connect(data interface{}) error {
 	if err := a.client.connect(opts, a); err != nil {
 		return fmt.Errorf("app: failed to connect to server: %v", err)
 	}
+	a.opts = opts
 
 	a.runtime.Events.Emit(eventClientConnected, opts.Addr)
 
@@ -147,12 +350,14 @@ func (a *api) loadProtoFiles(opts options) {
 			a.logger.Error("unable to load proto files via reflection: client is <nil>")
 		}
 		if a.protofiles, err = protoFilesFromReflectionAPI(a.client.conn, nil); err != nil {
-			//TODO Emit error to frontend
+			a.runtime.Events.Emit(eventProtoLoadError, err.Error())
 			a.logger.Errorf("error getting proto files from reflection API: %v", err)
 		}
-	}
-	if !opts.Reflect {
-		// TODO: load protos from disk
+	} else {
+		if a.protofiles, err = protoFilesFromDisk(opts.ProtoFiles, opts.ImportPaths); err != nil {
+			a.runtime.Events.Emit(eventProtoLoadError, err.Error())
+			a.logger.Errorf("error loading proto files from disk: %v", err)
+		}
 	}
 
 	a.emitServicesSelect()
@@ -203,7 +408,7 @@ func (a *api) setWorkspaceOptions(opts options) {
 	var val bytes.Buffer
 	enc := gob.NewEncoder(&val)
 	enc.Encode(opts)
-	a.store.set([]byte(defaultWorkspaceKey), val.Bytes())
+	a.store.set(workspaceDataKey(a.workspaceID), val.Bytes())
 }
 
 func (a *api) monitorStateChanges(ctx context.Context) {
@@ -312,7 +517,17 @@ func fieldViewsFromDesc(fds protoreflect.FieldDescriptors, isOneof bool) []field
 	return fields
 }
 
+// Send invokes method using the workspace's default metadata only.
 func (a *api) Send(method string, rawJSON []byte) error {
+	return a.SendWithMetadata(method, rawJSON, nil)
+}
+
+// SendWithMetadata invokes method, merging reqMD over the workspace's
+// default metadata (a key present in reqMD replaces that key's default
+// values) before attaching it to the outgoing call. A
+// "grpc-timeout" entry (e.g. "5s") is treated specially: it is applied
+// as the call's deadline rather than sent as a literal header.
+func (a *api) SendWithMetadata(method string, rawJSON []byte, reqMD map[string][]string) error {
 	md, err := a.getMethodDesc(method)
 	if err != nil {
 		return err
@@ -323,20 +538,76 @@ func (a *api) Send(method string, rawJSON []byte) error {
 		return err
 	}
 
+	a.mu.Lock()
 	if a.inFlight && md.IsStreamingClient() {
-		a.streamReq <- req
+		// Send this follow-up message while still holding a.mu so a
+		// concurrent endBidiStream/Cancel/CloseSend can't close
+		// a.streamReq out from under this send.
+		if a.streamReq != nil {
+			a.streamReq <- req
+		}
+		a.mu.Unlock()
 		return nil
 	}
-
-	a.mu.Lock()
-	defer a.mu.Unlock()
 	a.inFlight = true
+
+	if historyID, err := newID(); err != nil {
+		a.logger.Errorf("failed to start history entry: %v", err)
+	} else {
+		a.currentHistory = &historyEntry{
+			ID:          historyID,
+			Method:      method,
+			RequestJSON: string(rawJSON),
+			Metadata:    reqMD,
+		}
+	}
+
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	a.cancelInFlight = cancel
+	a.mu.Unlock()
+
+	// A client- or bidi-streaming call stays "in flight" for as long as
+	// its stream is open, so that further Send calls route additional
+	// request messages through a.streamReq instead of opening a new
+	// call. Only reset it here for calls that are fully done by the
+	// time this function returns; for a bidi stream it is reset, and
+	// a.cancelInFlight released, by endBidiStream once the stream itself
+	// finishes, or by Cancel.
+	resetInFlight := true
 	defer func() {
-		a.inFlight = false
+		if resetInFlight {
+			a.mu.Lock()
+			a.inFlight = false
+			a.mu.Unlock()
+			cancel()
+		}
 	}()
 
-	ctx := context.Background()
-	ctx, a.cancelInFlight = context.WithCancel(ctx)
+	outMD := metadata.MD(a.opts.DefaultMetadata).Copy()
+	for k, v := range reqMD {
+		outMD.Set(k, v...)
+	}
+	callCtx := ctx
+	if timeouts := outMD.Get("grpc-timeout"); len(timeouts) > 0 {
+		d, err := time.ParseDuration(timeouts[0])
+		if err != nil {
+			return fmt.Errorf("app: invalid grpc-timeout %q: %v", timeouts[0], err)
+		}
+		outMD.Delete("grpc-timeout")
+
+		var cancelTimeout context.CancelFunc
+		callCtx, cancelTimeout = context.WithTimeout(ctx, d)
+		prevCancel := cancel
+		cancel = func() {
+			cancelTimeout()
+			prevCancel()
+		}
+		a.mu.Lock()
+		a.cancelInFlight = cancel
+		a.mu.Unlock()
+	}
+	callCtx = metadata.NewOutgoingContext(callCtx, outMD)
 
 	a.runtime.Events.Emit(eventRPCStarted, rpcStart{
 		ClientStream: md.IsStreamingClient(),
@@ -344,45 +615,161 @@ func (a *api) Send(method string, rawJSON []byte) error {
 	})
 
 	if md.IsStreamingClient() && md.IsStreamingServer() {
-		//TODO(rogchao) manage bidi requests
+		stream, err := a.client.invokeBidiStream(callCtx, method)
+		if err != nil {
+			a.finalizeHistory()
+			return err
+		}
+
+		streamReq := make(chan proto.Message)
+
+		a.mu.Lock()
+		a.stream = stream
+		a.streamReq = streamReq
+		resetInFlight = false
+
+		go func() {
+			for r := range streamReq {
+				if err := stream.SendMsg(r); err != nil {
+					a.endBidiStream(stream)
+					return
+				}
+			}
+		}()
+
+		go func() {
+			for {
+				resp := dynamicpb.NewMessage(md.Output())
+				if err := stream.RecvMsg(resp); err != nil {
+					a.endBidiStream(stream)
+					return
+				}
+			}
+		}()
+
+		// Sent while still holding a.mu so endBidiStream (woken by
+		// either goroutine above) can't close streamReq out from under
+		// this send.
+		streamReq <- req
+		a.mu.Unlock()
+
 		return nil
 	}
 
 	if md.IsStreamingClient() {
-		stream, err := a.client.invokeClientStream(ctx, method)
+		var trl metadata.MD
+		stream, err := a.client.invokeClientStream(callCtx, method, grpc.Trailer(&trl))
 		if err != nil {
+			a.finalizeHistory()
 			return err
 		}
-		a.streamReq = make(chan proto.Message)
-		a.streamReq <- req
-		for r := range a.streamReq {
+
+		streamReq := make(chan proto.Message)
+		a.mu.Lock()
+		a.streamReq = streamReq
+		streamReq <- req
+		a.mu.Unlock()
+
+		for r := range streamReq {
 			if err := stream.SendMsg(r); err != nil {
-				close(a.streamReq)
+				a.mu.Lock()
+				if a.streamReq == streamReq {
+					a.streamReq = nil
+					close(streamReq)
+				}
+				a.mu.Unlock()
 			}
 		}
 		stream.CloseAndReceive()
+		stream.RecvMsg(dynamicpb.NewMessage(md.Output()))
+		// By now the single response has been received, so the headers
+		// (which a server always sends ahead of any response message)
+		// are already buffered and this returns without blocking.
+		if hdr, err := stream.Header(); err == nil {
+			a.emitResponseHeaders(hdr)
+		}
+		a.emitResponseTrailers(trl)
+		a.finalizeHistory()
 
 		return nil
 	}
 
 	if md.IsStreamingServer() {
-		stream, err := a.client.invokeServerStream(ctx, method, req)
+		var trl metadata.MD
+		stream, err := a.client.invokeServerStream(callCtx, method, req, grpc.Trailer(&trl))
 		if err != nil {
+			a.finalizeHistory()
 			return err
 		}
+		gotHeaders := false
 		for {
 			resp := dynamicpb.NewMessage(md.Output())
 			if err := stream.RecvMsg(resp); err != nil {
 				break
 			}
+			if !gotHeaders {
+				gotHeaders = true
+				if hdr, err := stream.Header(); err == nil {
+					a.emitResponseHeaders(hdr)
+				}
+			}
 		}
+		a.emitResponseTrailers(trl)
+		a.finalizeHistory()
 
 		return nil
 	}
 
-	resp := dynamicpb.NewMessage(md.Output())
-	a.client.invoke(ctx, method, req, resp)
-	return nil
+	retry := a.opts.Retry
+	for attempt := 1; ; attempt++ {
+		resp := dynamicpb.NewMessage(md.Output())
+		var hdr, trl metadata.MD
+		callErr := a.client.invoke(callCtx, method, req, resp, grpc.Header(&hdr), grpc.Trailer(&trl))
+		a.emitResponseHeaders(hdr)
+		a.emitResponseTrailers(trl)
+
+		code := status.Code(callErr)
+		if callErr == nil || !retry.Enabled || attempt >= retry.MaxAttempts || !isRetryableCode(code, retry.RetryableCodes) {
+			a.finalizeHistory()
+			return nil
+		}
+
+		delay := retryBackoff(retry, attempt)
+		a.runtime.Events.Emit(eventRPCRetry, rpcRetry{Attempt: attempt, Code: code.String(), Delay: delay.String()})
+
+		// This attempt failed but will be retried: drop its response
+		// text so the entry eventually persisted by finalizeHistory
+		// reflects only the attempt that actually finishes the call.
+		a.mu.Lock()
+		if a.currentHistory != nil {
+			a.currentHistory.Response = ""
+		}
+		a.mu.Unlock()
+
+		// Sleep without holding a.mu: a multi-second backoff must not
+		// block every other caller of a.mu (Cancel, CloseSend, a
+		// concurrent Send) for its full duration.
+		select {
+		case <-time.After(delay):
+		case <-callCtx.Done():
+			a.finalizeHistory()
+			return nil
+		}
+	}
+}
+
+func (a *api) emitResponseHeaders(md metadata.MD) {
+	if len(md) == 0 {
+		return
+	}
+	a.runtime.Events.Emit(eventResponseHeadersReceived, map[string][]string(md))
+}
+
+func (a *api) emitResponseTrailers(md metadata.MD) {
+	if len(md) == 0 {
+		return
+	}
+	a.runtime.Events.Emit(eventResponseTrailersReceived, map[string][]string(md))
 }
 
 // TagConn implements the stats.Handler interface
@@ -416,14 +803,55 @@ func (a *api) HandleRPC(ctx context.Context, stat stats.RPCStats) {
 			return
 		}
 		a.runtime.Events.Emit(eventInPayloadReceived, txt)
+
+		a.mu.Lock()
+		if a.currentHistory != nil {
+			if a.currentHistory.Response != "" {
+				a.currentHistory.Response += "\n"
+			}
+			a.currentHistory.Response += txt
+		}
+		a.mu.Unlock()
 	case *stats.End:
 		stus := status.Convert(s.Error)
-		var end rpcEnd
-		end.StatusCode = int32(stus.Code())
-		end.Status = stus.Code().String()
-		end.Duration = s.EndTime.Sub(s.BeginTime).String()
-		a.runtime.Events.Emit(eventRPCEnded, end)
 
+		// Only recorded here, not emitted or persisted: for a retried
+		// unary call this fires once per attempt, and finalizeHistory
+		// is what decides when the call is actually done.
+		a.mu.Lock()
+		if a.currentHistory != nil {
+			a.currentHistory.StatusCode = int32(stus.Code())
+			a.currentHistory.Status = stus.Code().String()
+			a.currentHistory.Duration = s.EndTime.Sub(s.BeginTime).String()
+		}
+		a.mu.Unlock()
+	}
+}
+
+// finalizeHistory emits eventRPCEnded and persists the in-progress
+// history entry using the status HandleRPC last recorded on it, then
+// clears it so the next Send starts fresh. Called once per logical
+// Send, at the point the call (including any retries) is actually
+// done, rather than from HandleRPC's *stats.End case, which fires once
+// per attempt.
+func (a *api) finalizeHistory() {
+	a.mu.Lock()
+	entry := a.currentHistory
+	a.currentHistory = nil
+	a.mu.Unlock()
+
+	if entry == nil {
+		return
+	}
+
+	a.runtime.Events.Emit(eventRPCEnded, rpcEnd{
+		StatusCode: entry.StatusCode,
+		Status:     entry.Status,
+		Duration:   entry.Duration,
+	})
+
+	if err := a.store.appendHistory(a.workspaceID, *entry); err != nil {
+		a.logger.Errorf("failed to record history: %v", err)
 	}
 }
 
@@ -450,9 +878,62 @@ func formatPayload(payload interface{}) (string, error) {
 	return string(b), nil
 }
 
-// Cancel will attempt to cancel the current inflight request
+// endBidiStream clears the bookkeeping for a bidirectional stream once
+// it has finished in both directions, marking the call no longer in
+// flight so the next Send opens a fresh stream. It is a no-op if stream
+// has already been superseded or torn down.
+func (a *api) endBidiStream(stream grpc.ClientStream) {
+	a.mu.Lock()
+	if a.stream != stream {
+		a.mu.Unlock()
+		return
+	}
+	if a.streamReq != nil {
+		close(a.streamReq)
+		a.streamReq = nil
+	}
+	a.stream = nil
+	a.inFlight = false
+	cancel := a.cancelInFlight
+	a.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	a.finalizeHistory()
+}
+
+// CloseSend half-closes the send side of the current bidirectional
+// stream, letting the user signal they have no more request messages
+// while still receiving whatever the server continues to send.
+func (a *api) CloseSend() error {
+	a.mu.Lock()
+	stream := a.stream
+	if a.streamReq != nil {
+		close(a.streamReq)
+		a.streamReq = nil
+	}
+	a.mu.Unlock()
+
+	if stream == nil {
+		return nil
+	}
+	return stream.CloseSend()
+}
+
+// Cancel will attempt to cancel the current inflight request, tearing
+// down both directions of a bidirectional stream if one is active.
 func (a *api) Cancel() {
 	if a.cancelInFlight != nil {
 		a.cancelInFlight()
 	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.streamReq != nil {
+		close(a.streamReq)
+		a.streamReq = nil
+	}
+	a.stream = nil
+	a.inFlight = false
 }
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/stats"
+)
+
+// client wraps a single grpc.ClientConn and the handful of invocation
+// shapes the UI needs: unary, client-streaming, server-streaming and
+// bidirectional-streaming.
+type client struct {
+	conn *grpc.ClientConn
+}
+
+// connect dials the server described by opts, routing all stats events
+// (connection begin/end, payloads, RPC begin/end) through handler.
+// Connectivity state changes are not reported to it. Transport security
+// and any per-call credentials are built from opts.Security.
+func (c *client) connect(opts options, handler stats.Handler) error {
+	transportCreds, err := transportCredentials(opts.Security)
+	if err != nil {
+		return fmt.Errorf("app: failed to build transport credentials: %v", err)
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithStatsHandler(handler),
+		grpc.WithTransportCredentials(transportCreds),
+	}
+
+	perRPCCreds, err := perRPCCredentials(opts.Security)
+	if err != nil {
+		return fmt.Errorf("app: failed to build call credentials: %v", err)
+	}
+	if perRPCCreds != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(perRPCCreds))
+	}
+
+	if opts.Retry.InitialBackoff > 0 {
+		dialOpts = append(dialOpts, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  opts.Retry.InitialBackoff,
+				Multiplier: opts.Retry.BackoffMultiplier,
+				Jitter:     opts.Retry.Jitter,
+				MaxDelay:   opts.Retry.MaxBackoff,
+			},
+			MinConnectTimeout: opts.Retry.MinConnectTimeout,
+		}))
+	}
+
+	// Per-method retries for unary calls are handled by the manual loop
+	// in api.SendWithMetadata, which is what emits eventRPCRetry for the
+	// UI's attempt counter. Do not also enable grpc's built-in
+	// transparent retry (grpc.WithDefaultServiceConfig) here, or a
+	// retryable unary call would be retried by both layers at once.
+
+	conn, err := grpc.Dial(opts.Addr, dialOpts...)
+	if err != nil {
+		return err
+	}
+
+	c.conn = conn
+	return nil
+}
+
+func (c *client) close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// invoke performs a single unary RPC.
+func (c *client) invoke(ctx context.Context, method string, req, resp interface{}, opts ...grpc.CallOption) error {
+	return c.conn.Invoke(ctx, method, req, resp, opts...)
+}
+
+// clientStream wraps a grpc.ClientStream for a client-streaming RPC,
+// adding a CloseAndReceive helper named after the one in the generated
+// stubs protoc would normally produce for a known message type.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+// CloseAndReceive half-closes the stream. It does not read the response:
+// the caller receives the single response message with RecvMsg.
+func (s *clientStream) CloseAndReceive() error {
+	return s.ClientStream.CloseSend()
+}
+
+func (c *client) invokeClientStream(ctx context.Context, method string, opts ...grpc.CallOption) (*clientStream, error) {
+	desc := &grpc.StreamDesc{StreamName: method, ClientStreams: true}
+	s, err := c.conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientStream{ClientStream: s}, nil
+}
+
+// invokeServerStream sends the single request message and returns the
+// stream the caller should drain with repeated RecvMsg calls.
+func (c *client) invokeServerStream(ctx context.Context, method string, req interface{}, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	desc := &grpc.StreamDesc{StreamName: method, ServerStreams: true}
+	s, err := c.conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := s.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// invokeBidiStream opens a full-duplex stream. The caller is responsible
+// for driving both SendMsg and RecvMsg; CloseSend half-closes the send
+// side once the caller has no more request messages.
+func (c *client) invokeBidiStream(ctx context.Context, method string) (grpc.ClientStream, error) {
+	desc := &grpc.StreamDesc{StreamName: method, ClientStreams: true, ServerStreams: true}
+	return c.conn.NewStream(ctx, desc, method)
+}
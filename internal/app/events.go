@@ -0,0 +1,44 @@
+package app
+
+// ctxInternalKey marks a context as originating from internal plumbing
+// (e.g. the reflection client) so HandleRPC can ignore its stats.
+type ctxInternalKey struct{}
+
+// Event names emitted to the frontend via runtime.Events.Emit.
+const (
+	eventClientConnected       = "client:connected"
+	eventClientStateChanged    = "client:state_changed"
+	eventServicesSelectChanged = "services:select_changed"
+	eventMethodInputChanged    = "method:input_changed"
+	eventRPCStarted            = "rpc:started"
+	eventRPCEnded              = "rpc:ended"
+	eventInPayloadReceived     = "rpc:in_payload_received"
+	eventProtoLoadError        = "proto:load_error"
+
+	eventResponseHeadersReceived  = "rpc:response_headers_received"
+	eventResponseTrailersReceived = "rpc:response_trailers_received"
+
+	eventRPCRetry = "rpc:retry"
+)
+
+// rpcStart is emitted when a new RPC is kicked off, so the frontend knows
+// which affordances (e.g. a "send" button for client streams) to show.
+type rpcStart struct {
+	ClientStream bool `json:"clientStream"`
+	ServerStream bool `json:"serverStream"`
+}
+
+// rpcEnd is emitted once an RPC (of any shape) has fully completed.
+type rpcEnd struct {
+	StatusCode int32  `json:"statusCode"`
+	Status     string `json:"status"`
+	Duration   string `json:"duration"`
+}
+
+// rpcRetry is emitted before each retried attempt of a unary call, so
+// the frontend can show an attempt count.
+type rpcRetry struct {
+	Attempt int    `json:"attempt"`
+	Code    string `json:"code"`
+	Delay   string `json:"delay"`
+}
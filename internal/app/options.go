@@ -0,0 +1,83 @@
+package app
+
+import "time"
+
+// options describes the per-workspace connection settings persisted to
+// the store between sessions.
+type options struct {
+	Addr    string `mapstructure:"addr"`
+	Reflect bool   `mapstructure:"reflect"`
+
+	// ProtoFiles and ImportPaths are only used when Reflect is false.
+	// ProtoFiles may contain literal .proto paths, glob patterns or
+	// directories (searched recursively for .proto files).
+	ProtoFiles  []string `mapstructure:"protoFiles"`
+	ImportPaths []string `mapstructure:"importPaths"`
+
+	// DefaultMetadata is sent with every RPC for this workspace (e.g. an
+	// auth token), merged under whatever per-call metadata Send is given.
+	DefaultMetadata map[string][]string `mapstructure:"defaultMetadata"`
+
+	Security securityOptions `mapstructure:"security"`
+
+	Retry retryOptions `mapstructure:"retry"`
+}
+
+// retryOptions configures both dial-level reconnection backoff and
+// per-method client-side retries for unary calls.
+type retryOptions struct {
+	// Enabled turns on the manual retry loop in Send for unary calls
+	// that come back with a RetryableCodes status.
+	Enabled        bool     `mapstructure:"enabled"`
+	MaxAttempts    int      `mapstructure:"maxAttempts"`
+	RetryableCodes []string `mapstructure:"retryableCodes"`
+
+	// InitialBackoff, MaxBackoff, BackoffMultiplier and Jitter describe
+	// the exponential backoff with jitter used both for dial-level
+	// reconnects (grpc.ConnectParams) and for the manual unary retry
+	// loop: delay_n = min(initial * multiplier^(n-1), max) * (1 ± jitter).
+	InitialBackoff    time.Duration `mapstructure:"initialBackoff"`
+	MaxBackoff        time.Duration `mapstructure:"maxBackoff"`
+	BackoffMultiplier float64       `mapstructure:"backoffMultiplier"`
+	Jitter            float64       `mapstructure:"jitter"`
+
+	// MinConnectTimeout is passed through to grpc.ConnectParams.
+	MinConnectTimeout time.Duration `mapstructure:"minConnectTimeout"`
+}
+
+// securityOptions describes how the client should dial the server:
+// the transport credentials (Mode) and, on top of that, any per-call
+// credentials (Credentials) attached to every RPC.
+type securityOptions struct {
+	// Mode is one of "plaintext" (the default), "system" (TLS against
+	// the system root CA pool) or "custom" (TLS against CACertFile,
+	// optionally presenting a client certificate for mutual TLS).
+	Mode string `mapstructure:"mode"`
+
+	CACertFile     string `mapstructure:"caCertFile"`
+	ClientCertFile string `mapstructure:"clientCertFile"`
+	ClientKeyFile  string `mapstructure:"clientKeyFile"`
+
+	// ServerNameOverride overrides the server name used for SNI and
+	// certificate verification, useful when dialing by IP.
+	ServerNameOverride string `mapstructure:"serverNameOverride"`
+
+	Credentials credentialOptions `mapstructure:"credentials"`
+}
+
+// credentialOptions describes a credentials.PerRPCCredentials plugin
+// attached to every outgoing call, on top of the transport security.
+type credentialOptions struct {
+	// Type is one of "" (none), "bearer", "basic" or "exec".
+	Type string `mapstructure:"type"`
+
+	BearerToken string `mapstructure:"bearerToken"`
+
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+
+	// ExecCommand is run for every RPC when Type is "exec"; its trimmed
+	// stdout is used as the bearer token, analogous to kubeconfig's
+	// exec auth plugin.
+	ExecCommand []string `mapstructure:"execCommand"`
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// expandProtoFiles resolves each of the given paths into a concrete list
+// of .proto files. A directory is walked recursively (so a user can
+// point at a monorepo root), anything else is treated as a glob pattern.
+func expandProtoFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err == nil && info.IsDir() {
+			err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if !info.IsDir() && strings.HasSuffix(path, ".proto") {
+					files = append(files, path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("app: failed to walk %q: %v", p, err)
+			}
+			continue
+		}
+
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("app: invalid proto file pattern %q: %v", p, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("app: no proto files matched %q", p)
+		}
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// protoFilesFromDisk parses protoFiles (expanding globs and directories)
+// resolving imports against importPaths, and merges the resulting
+// descriptors into a single registry.
+func protoFilesFromDisk(protoFiles, importPaths []string) (*protoregistry.Files, error) {
+	files, err := expandProtoFiles(protoFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := protoparse.Parser{
+		ImportPaths:      importPaths,
+		InferImportPaths: len(importPaths) == 0,
+	}
+
+	fds, err := parser.ParseFiles(files...)
+	if err != nil {
+		return nil, fmt.Errorf("app: failed to parse proto files: %v", err)
+	}
+
+	reg := new(protoregistry.Files)
+	seen := make(map[string]struct{})
+	for _, fd := range fds {
+		if err := registerFileAndDeps(fd, reg, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	return reg, nil
+}
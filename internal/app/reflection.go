@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// protoFilesFromReflectionAPI discovers every service exposed by the
+// server's reflection API (plus any extra fully-qualified service names)
+// and merges their file descriptors into a single registry.
+func protoFilesFromReflectionAPI(conn *grpc.ClientConn, extra []string) (*protoregistry.Files, error) {
+	ctx := context.WithValue(context.Background(), ctxInternalKey{}, true)
+	rc := grpcreflect.NewClientAuto(ctx, conn)
+	defer rc.Reset()
+
+	svcNames, err := rc.ListServices()
+	if err != nil {
+		return nil, fmt.Errorf("app: failed to list services via reflection: %v", err)
+	}
+	svcNames = append(svcNames, extra...)
+
+	files := new(protoregistry.Files)
+	seen := make(map[string]struct{})
+
+	for _, name := range svcNames {
+		sd, err := rc.ResolveService(name)
+		if err != nil {
+			return nil, fmt.Errorf("app: failed to resolve service %s: %v", name, err)
+		}
+		if err := registerFileAndDeps(sd.GetFile(), files, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// registerFileAndDeps recursively registers fd and all of its
+// dependencies into files, skipping any that have already been seen.
+func registerFileAndDeps(fd *desc.FileDescriptor, files *protoregistry.Files, seen map[string]struct{}) error {
+	if _, ok := seen[fd.GetName()]; ok {
+		return nil
+	}
+	seen[fd.GetName()] = struct{}{}
+
+	for _, dep := range fd.GetDependencies() {
+		if err := registerFileAndDeps(dep, files, seen); err != nil {
+			return err
+		}
+	}
+	return files.RegisterFile(fd.UnwrapFile())
+}
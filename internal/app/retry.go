@@ -0,0 +1,39 @@
+package app
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// retryBackoff computes the delay before retry attempt n (1-indexed), so
+// the first retry (attempt 1) waits the initial backoff unscaled:
+// delay_n = min(initial * multiplier^(n-1), max) * (1 ± jitter).
+func retryBackoff(r retryOptions, attempt int) time.Duration {
+	d := float64(r.InitialBackoff) * math.Pow(r.BackoffMultiplier, float64(attempt-1))
+	if r.MaxBackoff > 0 && d > float64(r.MaxBackoff) {
+		d = float64(r.MaxBackoff)
+	}
+	if r.Jitter > 0 {
+		d *= 1 + r.Jitter*(2*rand.Float64()-1)
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	return time.Duration(d)
+}
+
+// isRetryableCode reports whether code is one of the workspace's
+// configured retryable status codes (e.g. "Unavailable").
+func isRetryableCode(code codes.Code, retryable []string) bool {
+	for _, c := range retryable {
+		if strings.EqualFold(c, code.String()) {
+			return true
+		}
+	}
+	return false
+}
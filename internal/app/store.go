@@ -0,0 +1,58 @@
+package app
+
+import (
+	bolt "go.etcd.io/bbolt"
+)
+
+var storeBucket = []byte("wombat")
+
+// store is a thin key/value wrapper around a local bbolt database, used
+// to persist workspace options between runs.
+type store struct {
+	db *bolt.DB
+}
+
+func newStore(path string) (*store, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(storeBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &store{db: db}, nil
+}
+
+func (s *store) get(key []byte) ([]byte, error) {
+	var val []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(storeBucket).Get(key)
+		if v != nil {
+			val = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	return val, err
+}
+
+func (s *store) set(key, val []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(storeBucket).Put(key, val)
+	})
+}
+
+func (s *store) delete(key []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(storeBucket).Delete(key)
+	})
+}
+
+func (s *store) close() error {
+	return s.db.Close()
+}
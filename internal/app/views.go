@@ -0,0 +1,32 @@
+package app
+
+// servicesSelect is the view model used to populate the service/method
+// picker in the frontend.
+type servicesSelect []serviceSelect
+
+type serviceSelect struct {
+	FullName string         `json:"fullName"`
+	Methods  []methodSelect `json:"methods"`
+}
+
+type methodSelect struct {
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+}
+
+// messageDesc and fieldDesc are the view models used to render the
+// request editor for the currently selected method.
+type messageDesc struct {
+	FullName string      `json:"fullName"`
+	Fields   []fieldDesc `json:"fields"`
+}
+
+type fieldDesc struct {
+	Name     string       `json:"name"`
+	FullName string       `json:"fullName"`
+	Kind     string       `json:"kind"`
+	Repeated bool         `json:"repeated"`
+	Enum     []string     `json:"enum,omitempty"`
+	Message  *messageDesc `json:"message,omitempty"`
+	Oneof    []fieldDesc  `json:"oneof,omitempty"`
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/hex"
+)
+
+const (
+	workspaceRegistryKey = "workspaces"
+	currentWorkspaceKey  = "current_workspace"
+)
+
+// workspace is a named collection of connection options and request
+// history, analogous to a Postman collection/environment.
+type workspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// historyEntry records a single Send invocation so it can be listed and
+// replayed later.
+type historyEntry struct {
+	ID          string              `json:"id"`
+	Method      string              `json:"method"`
+	RequestJSON string              `json:"requestJson"`
+	Metadata    map[string][]string `json:"metadata,omitempty"`
+	Response    string              `json:"response,omitempty"`
+	StatusCode  int32               `json:"statusCode,omitempty"`
+	Status      string              `json:"status,omitempty"`
+	Duration    string              `json:"duration,omitempty"`
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func workspaceDataKey(id string) []byte {
+	return []byte("wksp_" + id)
+}
+
+func workspaceHistoryKey(id string) []byte {
+	return []byte("wksp_" + id + "_history")
+}
+
+func (s *store) listWorkspaces() ([]workspace, error) {
+	val, err := s.get([]byte(workspaceRegistryKey))
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, nil
+	}
+
+	var workspaces []workspace
+	dec := gob.NewDecoder(bytes.NewBuffer(val))
+	if err := dec.Decode(&workspaces); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+func (s *store) saveWorkspaces(workspaces []workspace) error {
+	var val bytes.Buffer
+	if err := gob.NewEncoder(&val).Encode(workspaces); err != nil {
+		return err
+	}
+	return s.set([]byte(workspaceRegistryKey), val.Bytes())
+}
+
+func (s *store) listHistory(workspaceID string) ([]historyEntry, error) {
+	val, err := s.get(workspaceHistoryKey(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, nil
+	}
+
+	var history []historyEntry
+	dec := gob.NewDecoder(bytes.NewBuffer(val))
+	if err := dec.Decode(&history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (s *store) appendHistory(workspaceID string, entry historyEntry) error {
+	history, err := s.listHistory(workspaceID)
+	if err != nil {
+		return err
+	}
+	history = append(history, entry)
+
+	var val bytes.Buffer
+	if err := gob.NewEncoder(&val).Encode(history); err != nil {
+		return err
+	}
+	return s.set(workspaceHistoryKey(workspaceID), val.Bytes())
+}